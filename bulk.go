@@ -0,0 +1,186 @@
+package dnsify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const defaultBulkWorkers = 10
+
+var errFailedAfterRetries = errors.New("failed to resolve after max retries")
+
+// BulkResult is the outcome of resolving a single host as part of a
+// ResolveBulk batch.
+type BulkResult struct {
+	Host     string
+	Records  []string
+	Raw      *dns.Msg
+	Resolver string
+	Err      error
+}
+
+// BulkOption configures a ResolveBulk call.
+type BulkOption func(*bulkConfig)
+
+type bulkConfig struct {
+	workers    int
+	ratePerSec int
+}
+
+// WithWorkers sets the number of worker goroutines used to resolve hosts
+// concurrently. It defaults to defaultBulkWorkers.
+func WithWorkers(n int) BulkOption {
+	return func(cfg *bulkConfig) {
+		if n > 0 {
+			cfg.workers = n
+		}
+	}
+}
+
+// WithRateLimit caps the number of hosts started per second across all
+// workers. Retries of a host against other resolvers take no further
+// token. A value of 0 (the default) disables rate limiting.
+func WithRateLimit(queriesPerSecond int) BulkOption {
+	return func(cfg *bulkConfig) {
+		cfg.ratePerSec = queriesPerSecond
+	}
+}
+
+// ResolveBulk resolves hosts concurrently across a pool of worker
+// goroutines, streaming results back as they complete. The returned
+// channel is closed once every host has been resolved or ctx is
+// cancelled.
+func (c *Client) ResolveBulk(ctx context.Context, hosts []string, qtype uint16, opts ...BulkOption) (<-chan BulkResult, error) {
+	cfg := bulkConfig{workers: defaultBulkWorkers}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var limiter *tokenBucket
+	if cfg.ratePerSec > 0 {
+		limiter = newTokenBucket(cfg.ratePerSec)
+	}
+
+	jobs := make(chan string)
+	results := make(chan BulkResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range jobs {
+				if limiter != nil {
+					if err := limiter.Wait(ctx); err != nil {
+						results <- BulkResult{Host: host, Err: err}
+						continue
+					}
+				}
+				results <- c.resolveBulkOne(ctx, host, qtype)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, host := range hosts {
+			select {
+			case jobs <- host:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		if limiter != nil {
+			limiter.stop()
+		}
+		close(results)
+	}()
+
+	return results, nil
+}
+
+// resolveBulkOne resolves a single host for ResolveBulk, recording which
+// resolver ultimately answered.
+func (c *Client) resolveBulkOne(ctx context.Context, host string, qtype uint16) BulkResult {
+	msg := buildDNSMessage(host, qtype)
+	c.applyEDNS0(msg)
+
+	for i := 0; i < c.maxRetries; i++ {
+		resolver := c.pickResolver()
+
+		answer, err := c.exchangeAndReport(ctx, msg, resolver)
+		if err != nil || answer == nil || !isTerminalRcode(answer.Rcode) {
+			if ctx.Err() != nil {
+				return BulkResult{Host: host, Err: ctx.Err()}
+			}
+			continue
+		}
+
+		return BulkResult{
+			Host:     host,
+			Records:  parseRecordsByType(answer, qtype),
+			Raw:      answer,
+			Resolver: resolver,
+		}
+	}
+
+	return BulkResult{Host: host, Err: errFailedAfterRetries}
+}
+
+// tokenBucket is a simple token-bucket rate limiter that starts empty,
+// gains one token every 1/ratePerSec of a second, and holds at most
+// ratePerSec tokens.
+type tokenBucket struct {
+	tokens chan struct{}
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+func newTokenBucket(ratePerSec int) *tokenBucket {
+	tb := &tokenBucket{
+		tokens: make(chan struct{}, ratePerSec),
+		ticker: time.NewTicker(time.Second / time.Duration(ratePerSec)),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-tb.ticker.C:
+				select {
+				case tb.tokens <- struct{}{}:
+				default:
+				}
+			case <-tb.done:
+				tb.ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return tb
+}
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (tb *tokenBucket) Wait(ctx context.Context) error {
+	select {
+	case <-tb.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop terminates the refill goroutine and its underlying ticker. It
+// must be called exactly once per tokenBucket.
+func (tb *tokenBucket) stop() {
+	close(tb.done)
+}
@@ -0,0 +1,165 @@
+package dnsify
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// cacheKey uniquely identifies a cached query by name, type and class.
+type cacheKey struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+}
+
+// cacheEntry holds a cached response along with when it was stored and
+// the time at which it should be considered expired (its "time to die").
+type cacheEntry struct {
+	msg      *dns.Msg
+	cachedAt time.Time
+	ttd      time.Time
+}
+
+// Cache is the interface implemented by pluggable DNS response caches.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns a cached response for the given question, if present
+	// and not yet expired. The returned message has its answer TTLs
+	// adjusted for the age of the entry.
+	Get(name string, qtype, qclass uint16) (*dns.Msg, bool)
+	// Set stores resp under the given question, deriving its expiry
+	// from the minimum TTL across the answer RRset (or, for negative
+	// responses, the SOA minimum in the authority section).
+	Set(name string, qtype, qclass uint16, resp *dns.Msg)
+}
+
+// CacheStats reports cumulative cache hit/miss counters.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// TTLCache is the default in-memory Cache implementation. Entries expire
+// according to the TTLs observed in the cached response and are evicted
+// lazily on lookup.
+type TTLCache struct {
+	mutex   sync.RWMutex
+	entries map[cacheKey]cacheEntry
+
+	hits   int64
+	misses int64
+}
+
+// NewTTLCache creates an empty TTL-aware in-memory cache.
+func NewTTLCache() *TTLCache {
+	return &TTLCache{
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Get implements Cache.
+func (c *TTLCache) Get(name string, qtype, qclass uint16) (*dns.Msg, bool) {
+	key := cacheKey{name: dns.Fqdn(name), qtype: qtype, qclass: qclass}
+
+	c.mutex.RLock()
+	entry, ok := c.entries[key]
+	c.mutex.RUnlock()
+
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	if !time.Now().Before(entry.ttd) {
+		c.mutex.Lock()
+		delete(c.entries, key)
+		c.mutex.Unlock()
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	return ageMsg(entry.msg, time.Since(entry.cachedAt)), true
+}
+
+// Set implements Cache.
+func (c *TTLCache) Set(name string, qtype, qclass uint16, resp *dns.Msg) {
+	ttl := minTTL(resp)
+	if ttl <= 0 {
+		return
+	}
+
+	key := cacheKey{name: dns.Fqdn(name), qtype: qtype, qclass: qclass}
+	now := time.Now()
+	entry := cacheEntry{
+		msg:      resp.Copy(),
+		cachedAt: now,
+		ttd:      now.Add(time.Duration(ttl) * time.Second),
+	}
+
+	c.mutex.Lock()
+	c.entries[key] = entry
+	c.mutex.Unlock()
+}
+
+// Stats returns the cumulative hit/miss counters for this cache.
+func (c *TTLCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
+
+// minTTL returns the TTL to cache resp under: the minimum TTL across the
+// answer RRset for a positive response, or the SOA minimum from the
+// authority section for NXDOMAIN/NODATA responses per RFC 2308.
+func minTTL(resp *dns.Msg) uint32 {
+	if len(resp.Answer) > 0 {
+		var min uint32
+		for i, rr := range resp.Answer {
+			ttl := rr.Header().Ttl
+			if i == 0 || ttl < min {
+				min = ttl
+			}
+		}
+		return min
+	}
+
+	if resp.Rcode == dns.RcodeNameError || resp.Rcode == dns.RcodeSuccess {
+		for _, rr := range resp.Ns {
+			if soa, ok := rr.(*dns.SOA); ok {
+				return soa.Minttl
+			}
+		}
+	}
+
+	return 0
+}
+
+// ageMsg returns a copy of msg with every answer TTL decremented by the
+// age of the cache entry, floored at zero.
+func ageMsg(msg *dns.Msg, age time.Duration) *dns.Msg {
+	aged := msg.Copy()
+	elapsed := uint32(age.Seconds())
+
+	for _, rr := range aged.Answer {
+		hdr := rr.Header()
+		if hdr.Ttl > elapsed {
+			hdr.Ttl -= elapsed
+		} else {
+			hdr.Ttl = 0
+		}
+	}
+
+	return aged
+}
+
+// WithCache attaches a Cache to the client, enabling cache lookups and
+// population for Resolve, ResolveRaw and Do.
+func (c *Client) WithCache(cache Cache) *Client {
+	c.cache = cache
+	return c
+}
@@ -0,0 +1,69 @@
+package dnsify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTTLCacheDecrementsByElapsedTime tests that a cache hit returns a
+// TTL decremented by how long the entry has been cached, not by how
+// long remains until it expires.
+func TestTTLCacheDecrementsByElapsedTime(t *testing.T) {
+	cache := NewTTLCache()
+
+	rr, err := dns.NewRR("example.com. 300 IN A 192.0.2.1")
+	assert.NoError(t, err)
+	resp := new(dns.Msg)
+	resp.Answer = append(resp.Answer, rr)
+
+	cache.Set("example.com.", dns.TypeA, dns.ClassINET, resp)
+
+	cached, ok := cache.Get("example.com.", dns.TypeA, dns.ClassINET)
+	assert.True(t, ok)
+	assert.InDelta(t, 300, cached.Answer[0].Header().Ttl, 1)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	cached, ok = cache.Get("example.com.", dns.TypeA, dns.ClassINET)
+	assert.True(t, ok)
+	assert.LessOrEqual(t, cached.Answer[0].Header().Ttl, uint32(299))
+	assert.Greater(t, cached.Answer[0].Header().Ttl, uint32(290))
+}
+
+// TestResolveRawCachesNXDOMAIN tests that an NXDOMAIN response is treated
+// as a terminal, cacheable answer rather than a retry-able failure.
+func TestResolveRawCachesNXDOMAIN(t *testing.T) {
+	dns.HandleFunc("nxdomain.example.", func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetRcode(req, dns.RcodeNameError)
+		soa, _ := dns.NewRR("example. 3600 IN SOA ns.example. hostmaster.example. 1 3600 900 604800 120")
+		m.Ns = append(m.Ns, soa)
+		_ = w.WriteMsg(m)
+	})
+	defer dns.HandleRemove("nxdomain.example.")
+
+	server, err := setupMockDNSServer()
+	if err != nil {
+		t.Fatalf("Failed to set up mock DNS server: %v", err)
+	}
+	defer server.Shutdown()
+
+	client := New([]string{mockDNS}, 3).WithCache(NewTTLCache())
+
+	results, _, err := client.ResolveRaw(context.Background(), "nxdomain.example", dns.TypeA)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	stats := client.cache.(*TTLCache).Stats()
+	assert.Equal(t, int64(0), stats.Hits)
+
+	_, _, err = client.ResolveRaw(context.Background(), "nxdomain.example", dns.TypeA)
+	assert.NoError(t, err)
+
+	stats = client.cache.(*TTLCache).Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+}
@@ -1,8 +1,9 @@
 package dnsify
 
 import (
+	"context"
 	"errors"
-	"math/rand"
+	"net/http"
 	"sync"
 	"time"
 
@@ -12,11 +13,24 @@ import (
 const defaultPort = "53"
 
 // Client is a DNS resolver client to resolve hostnames.
+//
+// Resolver addresses may be plain host[:port] (UDP), or carry a
+// udp://, tcp://, tls://, or https:// scheme to select the transport
+// used to reach that resolver.
 type Client struct {
 	resolvers  []string
 	maxRetries int
-	rand       *rand.Rand
 	mutex      sync.RWMutex
+
+	udpClient  *dns.Client
+	tcpClient  *dns.Client
+	tlsClient  *dns.Client
+	httpClient *http.Client
+
+	cache    Cache
+	edns     ednsOptions
+	selector ResolverSelector
+	tsig     tsigOptions
 }
 
 // Result contains the results from a DNS resolution.
@@ -27,26 +41,46 @@ type Result struct {
 
 // New creates a new DNS client.
 func New(baseResolvers []string, maxRetries int) *Client {
+	udpClient, tcpClient, tlsClient, httpClient := newTransports()
+
 	return &Client{
-		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
 		maxRetries: maxRetries,
 		resolvers:  baseResolvers,
+		udpClient:  udpClient,
+		tcpClient:  tcpClient,
+		tlsClient:  tlsClient,
+		httpClient: httpClient,
+		selector:   NewHealthAwareSelector(),
 	}
 }
 
 // Resolve resolves a hostname and retrieves its A record IPs and TTL.
-func (c *Client) Resolve(host string) (Result, error) {
+func (c *Client) Resolve(ctx context.Context, host string) (Result, error) {
 	msg := buildDNSMessage(host, dns.TypeA)
+	c.applyEDNS0(msg)
+
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(msg.Question[0].Name, dns.TypeA, dns.ClassINET); ok {
+			return parseARecords(cached), nil
+		}
+	}
 
 	var result Result
 	for i := 0; i < c.maxRetries; i++ {
-		resolver := c.getRandomResolver()
+		resolver := c.pickResolver()
 
-		answer, err := dns.Exchange(msg, resolver)
-		if err != nil || answer == nil || answer.Rcode != dns.RcodeSuccess {
+		answer, err := c.exchangeAndReport(ctx, msg, resolver)
+		if err != nil || answer == nil || !isTerminalRcode(answer.Rcode) {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
 			continue
 		}
 
+		if c.cache != nil {
+			c.cache.Set(msg.Question[0].Name, dns.TypeA, dns.ClassINET, answer)
+		}
+
 		return parseARecords(answer), nil
 	}
 
@@ -54,17 +88,31 @@ func (c *Client) Resolve(host string) (Result, error) {
 }
 
 // ResolveRaw resolves a hostname and retrieves raw DNS records of a specific type.
-func (c *Client) ResolveRaw(host string, requestType uint16) ([]string, string, error) {
+func (c *Client) ResolveRaw(ctx context.Context, host string, requestType uint16) ([]string, string, error) {
 	msg := buildDNSMessage(host, requestType)
+	c.applyEDNS0(msg)
+
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(msg.Question[0].Name, requestType, dns.ClassINET); ok {
+			return parseRecordsByType(cached, requestType), cached.String(), nil
+		}
+	}
 
 	for i := 0; i < c.maxRetries; i++ {
-		resolver := c.getRandomResolver()
+		resolver := c.pickResolver()
 
-		answer, err := dns.Exchange(msg, resolver)
-		if err != nil || answer == nil || answer.Rcode != dns.RcodeSuccess {
+		answer, err := c.exchangeAndReport(ctx, msg, resolver)
+		if err != nil || answer == nil || !isTerminalRcode(answer.Rcode) {
+			if ctx.Err() != nil {
+				return nil, "", ctx.Err()
+			}
 			continue
 		}
 
+		if c.cache != nil {
+			c.cache.Set(msg.Question[0].Name, requestType, dns.ClassINET, answer)
+		}
+
 		raw := answer.String()
 		return parseRecordsByType(answer, requestType), raw, nil
 	}
@@ -73,24 +121,62 @@ func (c *Client) ResolveRaw(host string, requestType uint16) ([]string, string,
 }
 
 // Do sends a DNS request and returns the raw DNS response.
-func (c *Client) Do(msg *dns.Msg) (*dns.Msg, error) {
+func (c *Client) Do(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	var q dns.Question
+	if len(msg.Question) > 0 {
+		q = msg.Question[0]
+	}
+
+	if c.cache != nil && q.Name != "" {
+		if cached, ok := c.cache.Get(q.Name, q.Qtype, q.Qclass); ok {
+			return cached, nil
+		}
+	}
+
 	for i := 0; i < c.maxRetries; i++ {
-		resolver := c.getRandomResolver()
+		resolver := c.pickResolver()
 
-		resp, err := dns.Exchange(msg, resolver)
+		resp, err := c.exchangeAndReport(ctx, msg, resolver)
 		if err == nil && resp != nil {
+			if c.cache != nil && q.Name != "" {
+				c.cache.Set(q.Name, q.Qtype, q.Qclass, resp)
+			}
 			return resp, nil
 		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 	}
 
 	return nil, errors.New("failed to send DNS request after max retries")
 }
 
-// getRandomResolver selects a random DNS resolver from the list.
-func (c *Client) getRandomResolver() string {
+// pickResolver asks the client's ResolverSelector to choose a resolver
+// from the configured list.
+func (c *Client) pickResolver() string {
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	return c.resolvers[c.rand.Intn(len(c.resolvers))]
+	resolvers := c.resolvers
+	c.mutex.RUnlock()
+	return c.selector.Select(resolvers)
+}
+
+// exchangeAndReport performs the exchange and reports its latency and
+// outcome to the client's ResolverSelector.
+func (c *Client) exchangeAndReport(ctx context.Context, msg *dns.Msg, resolver string) (*dns.Msg, error) {
+	start := time.Now()
+	resp, err := c.exchange(ctx, msg, resolver)
+	success := err == nil && resp != nil && isTerminalRcode(resp.Rcode)
+	c.selector.Report(resolver, time.Since(start), success)
+	return resp, err
+}
+
+// isTerminalRcode reports whether rcode is a definitive answer that
+// should stop the retry loop rather than be retried against another
+// resolver. NXDOMAIN is terminal (and, like NODATA, cacheable as a
+// negative response per RFC 2308); anything else that isn't a plain
+// success (e.g. SERVFAIL, REFUSED) is treated as retryable.
+func isTerminalRcode(rcode int) bool {
+	return rcode == dns.RcodeSuccess || rcode == dns.RcodeNameError
 }
 
 // buildDNSMessage constructs a DNS message with the given host and request type.
@@ -1,7 +1,9 @@
 package dnsify
 
 import (
+	"context"
 	"testing"
+
 	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,16 +28,19 @@ func mockDNSHandler(w dns.ResponseWriter, req *dns.Msg) {
 	_ = w.WriteMsg(m)
 }
 
-// setupMockDNSServer starts a mock DNS server on the localhost
+// setupMockDNSServer starts a mock DNS server on the localhost and
+// blocks until it is accepting connections.
 func setupMockDNSServer() (*dns.Server, error) {
 	dns.HandleFunc(".", mockDNSHandler)
 
-	server := &dns.Server{Addr: mockDNS, Net: "udp"}
+	started := make(chan struct{})
+	server := &dns.Server{Addr: mockDNS, Net: "udp", NotifyStartedFunc: func() { close(started) }}
 	go func() {
 		if err := server.ListenAndServe(); err != nil {
 			panic(err)
 		}
 	}()
+	<-started
 	return server, nil
 }
 
@@ -48,7 +53,7 @@ func TestResolve(t *testing.T) {
 	defer server.Shutdown()
 
 	client := New([]string{mockDNS}, 3)
-	result, err := client.Resolve("example.com")
+	result, err := client.Resolve(context.Background(), "example.com")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, result.IPs)
 	assert.Equal(t, "192.0.2.1", result.IPs[0])
@@ -64,7 +69,7 @@ func TestResolveRaw(t *testing.T) {
 	defer server.Shutdown()
 
 	client := New([]string{mockDNS}, 3)
-	results, raw, err := client.ResolveRaw("example.com", dns.TypeMX)
+	results, raw, err := client.ResolveRaw(context.Background(), "example.com", dns.TypeMX)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, results)
 	assert.Contains(t, raw, "mail.example.com")
@@ -84,8 +89,31 @@ func TestDo(t *testing.T) {
 	msg := new(dns.Msg)
 	msg.SetQuestion(dns.Fqdn("example.com"), dns.TypeA)
 
-	resp, err := client.Do(msg)
+	resp, err := client.Do(context.Background(), msg)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "example.com.\t3600\tIN\tA\t192.0.2.1", resp.Answer[0].String())
 }
+
+// TestResolveBulk tests that ResolveBulk resolves every host in a batch
+func TestResolveBulk(t *testing.T) {
+	server, err := setupMockDNSServer()
+	if err != nil {
+		t.Fatalf("Failed to set up mock DNS server: %v", err)
+	}
+	defer server.Shutdown()
+
+	client := New([]string{mockDNS}, 3)
+
+	hosts := []string{"one.example.com", "two.example.com", "three.example.com"}
+	resultCh, err := client.ResolveBulk(context.Background(), hosts, dns.TypeA, WithWorkers(2))
+	assert.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for result := range resultCh {
+		assert.NoError(t, result.Err)
+		assert.NotEmpty(t, result.Records)
+		seen[result.Host] = true
+	}
+	assert.Len(t, seen, len(hosts))
+}
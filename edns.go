@@ -0,0 +1,73 @@
+package dnsify
+
+import (
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// ednsOptions holds the EDNS0 settings applied to the queries built by
+// Resolve, ResolveRaw and ResolveBulk. Messages passed to Do and zone
+// transfer requests are sent without them.
+type ednsOptions struct {
+	enabled   bool
+	bufsize   uint16
+	dnssecOK  bool
+	subnet    net.IP
+	prefixLen uint8
+	hasSubnet bool
+}
+
+// WithEDNS0 enables EDNS0 on the queries built by Resolve, ResolveRaw
+// and ResolveBulk, with the given UDP buffer size and DNSSEC OK bit.
+func (c *Client) WithEDNS0(bufsize uint16, dnssecOK bool) *Client {
+	c.edns.enabled = true
+	c.edns.bufsize = bufsize
+	c.edns.dnssecOK = dnssecOK
+	return c
+}
+
+// WithClientSubnet enables the EDNS0 Client Subnet option (RFC 7871) on
+// the same queries as WithEDNS0, reporting ip truncated to prefixLen bits as the
+// source network. Implies WithEDNS0 if it has not already been called.
+func (c *Client) WithClientSubnet(ip net.IP, prefixLen uint8) *Client {
+	if !c.edns.enabled {
+		c.WithEDNS0(dns.DefaultMsgSize, false)
+	}
+	c.edns.subnet = ip
+	c.edns.prefixLen = prefixLen
+	c.edns.hasSubnet = true
+	return c
+}
+
+// applyEDNS0 attaches the OPT RR (and Client Subnet option, if
+// configured) to msg according to the client's EDNS0 settings.
+func (c *Client) applyEDNS0(msg *dns.Msg) {
+	if !c.edns.enabled {
+		return
+	}
+
+	msg.SetEdns0(c.edns.bufsize, c.edns.dnssecOK)
+
+	if !c.edns.hasSubnet {
+		return
+	}
+
+	family := uint16(1)
+	ip := c.edns.subnet.To4()
+	if ip == nil {
+		family = 2
+		ip = c.edns.subnet.To16()
+	}
+
+	subnet := &dns.EDNS0_SUBNET{
+		Code:          dns.EDNS0SUBNET,
+		Family:        family,
+		SourceNetmask: c.edns.prefixLen,
+		SourceScope:   0,
+		Address:       ip,
+	}
+
+	opt := msg.IsEdns0()
+	opt.Option = append(opt.Option, subnet)
+}
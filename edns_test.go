@@ -0,0 +1,54 @@
+package dnsify
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyEDNS0 tests that WithEDNS0 attaches an OPT RR carrying the
+// configured buffer size and DNSSEC OK bit.
+func TestApplyEDNS0(t *testing.T) {
+	client := New([]string{mockDNS}, 3).WithEDNS0(4096, true)
+
+	msg := buildDNSMessage("example.com", dns.TypeA)
+	client.applyEDNS0(msg)
+
+	opt := msg.IsEdns0()
+	assert.NotNil(t, opt)
+	assert.Equal(t, uint16(4096), opt.UDPSize())
+	assert.True(t, opt.Do())
+}
+
+// TestApplyEDNS0WithClientSubnet tests that WithClientSubnet attaches an
+// EDNS0_SUBNET option alongside the OPT RR, implying EDNS0 even if
+// WithEDNS0 was never called.
+func TestApplyEDNS0WithClientSubnet(t *testing.T) {
+	client := New([]string{mockDNS}, 3).WithClientSubnet(net.ParseIP("203.0.113.0"), 24)
+
+	msg := buildDNSMessage("example.com", dns.TypeA)
+	client.applyEDNS0(msg)
+
+	opt := msg.IsEdns0()
+	assert.NotNil(t, opt)
+	assert.Len(t, opt.Option, 1)
+
+	subnet, ok := opt.Option[0].(*dns.EDNS0_SUBNET)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(1), subnet.Family)
+	assert.Equal(t, uint8(24), subnet.SourceNetmask)
+	assert.Equal(t, "203.0.113.0", subnet.Address.String())
+}
+
+// TestApplyEDNS0NoOp tests that a client with no EDNS0 options configured
+// leaves the message untouched.
+func TestApplyEDNS0NoOp(t *testing.T) {
+	client := New([]string{mockDNS}, 3)
+
+	msg := buildDNSMessage("example.com", dns.TypeA)
+	client.applyEDNS0(msg)
+
+	assert.Nil(t, msg.IsEdns0())
+}
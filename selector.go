@@ -0,0 +1,203 @@
+package dnsify
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// failureThreshold is the number of consecutive failures after
+	// which a resolver is quarantined.
+	failureThreshold = 5
+	// quarantineBase is the initial backoff applied when a resolver is
+	// first quarantined; it doubles on every subsequent quarantine.
+	quarantineBase = 2 * time.Second
+	// quarantineMax caps the exponential backoff applied to a
+	// repeatedly failing resolver.
+	quarantineMax = 2 * time.Minute
+	// ewmaAlpha weights how much a new latency sample contributes to
+	// the running average.
+	ewmaAlpha = 0.2
+)
+
+// ResolverSelector picks which resolver to use for the next query and
+// records the outcome of each attempt so future picks can avoid
+// unhealthy upstreams.
+type ResolverSelector interface {
+	// Select returns one of resolvers to query next.
+	Select(resolvers []string) string
+	// Report records the outcome of a query sent to resolver.
+	Report(resolver string, latency time.Duration, success bool)
+	// Stats returns a snapshot of the tracked state for every resolver.
+	Stats() map[string]ResolverStat
+}
+
+// ResolverStat is a point-in-time snapshot of a resolver's health as
+// tracked by the default ResolverSelector.
+type ResolverStat struct {
+	Successes           int64
+	Failures            int64
+	ConsecutiveFailures int
+	EWMALatency         time.Duration
+	Quarantined         bool
+	QuarantinedUntil    time.Time
+}
+
+// resolverHealth is the internal, mutable health record for one resolver.
+type resolverHealth struct {
+	successes           int64
+	failures            int64
+	consecutiveFailures int
+	ewmaLatency         time.Duration
+	quarantinedUntil    time.Time
+	quarantineStreak    int
+}
+
+// healthAwareSelector is the default ResolverSelector. It weights
+// resolvers by lifetime success rate and EWMA latency, favors the better of two
+// random choices (power-of-two-choices), and temporarily quarantines
+// resolvers that fail too many times in a row.
+type healthAwareSelector struct {
+	mutex  sync.Mutex
+	rand   *rand.Rand
+	health map[string]*resolverHealth
+}
+
+// NewHealthAwareSelector creates the default weighted, health-aware
+// ResolverSelector.
+func NewHealthAwareSelector() ResolverSelector {
+	return &healthAwareSelector{
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		health: make(map[string]*resolverHealth),
+	}
+}
+
+// Select implements ResolverSelector using power-of-two-choices: it
+// samples two candidates (falling back to one when only one resolver is
+// eligible) and returns whichever scores better.
+func (s *healthAwareSelector) Select(resolvers []string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	eligible := s.eligibleLocked(resolvers)
+	if len(eligible) == 0 {
+		// Every resolver is quarantined; fall back to choosing among all
+		// of them rather than failing outright.
+		eligible = resolvers
+	}
+
+	first := eligible[s.rand.Intn(len(eligible))]
+	if len(eligible) == 1 {
+		return first
+	}
+
+	second := eligible[s.rand.Intn(len(eligible))]
+	if s.scoreLocked(second) > s.scoreLocked(first) {
+		return second
+	}
+	return first
+}
+
+// eligibleLocked returns the resolvers that are not currently
+// quarantined, probing a quarantined resolver once its backoff elapses.
+func (s *healthAwareSelector) eligibleLocked(resolvers []string) []string {
+	now := time.Now()
+	eligible := make([]string, 0, len(resolvers))
+	for _, r := range resolvers {
+		h := s.health[r]
+		if h == nil || !now.Before(h.quarantinedUntil) {
+			eligible = append(eligible, r)
+		}
+	}
+	return eligible
+}
+
+// scoreLocked computes a selection score for resolver: higher is
+// better. It rewards a high success rate and penalizes high latency.
+func (s *healthAwareSelector) scoreLocked(resolver string) float64 {
+	h := s.health[resolver]
+	if h == nil {
+		return 1
+	}
+
+	total := h.successes + h.failures
+	if total == 0 {
+		return 1
+	}
+
+	successRate := float64(h.successes) / float64(total)
+	latencyPenalty := float64(h.ewmaLatency) / float64(time.Second)
+	return successRate - 0.1*latencyPenalty
+}
+
+// Report implements ResolverSelector.
+func (s *healthAwareSelector) Report(resolver string, latency time.Duration, success bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	h := s.health[resolver]
+	if h == nil {
+		h = &resolverHealth{}
+		s.health[resolver] = h
+	}
+
+	if h.ewmaLatency == 0 {
+		h.ewmaLatency = latency
+	} else {
+		h.ewmaLatency = time.Duration(ewmaAlpha*float64(latency) + (1-ewmaAlpha)*float64(h.ewmaLatency))
+	}
+
+	if success {
+		h.successes++
+		h.consecutiveFailures = 0
+		h.quarantineStreak = 0
+		return
+	}
+
+	h.failures++
+	h.consecutiveFailures++
+
+	if h.consecutiveFailures >= failureThreshold {
+		backoff := quarantineBase << h.quarantineStreak
+		if backoff > quarantineMax || backoff <= 0 {
+			backoff = quarantineMax
+		} else {
+			h.quarantineStreak++
+		}
+		h.quarantinedUntil = time.Now().Add(backoff)
+	}
+}
+
+// Stats implements ResolverSelector.
+func (s *healthAwareSelector) Stats() map[string]ResolverStat {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := make(map[string]ResolverStat, len(s.health))
+	now := time.Now()
+	for resolver, h := range s.health {
+		stats[resolver] = ResolverStat{
+			Successes:           h.successes,
+			Failures:            h.failures,
+			ConsecutiveFailures: h.consecutiveFailures,
+			EWMALatency:         h.ewmaLatency,
+			Quarantined:         now.Before(h.quarantinedUntil),
+			QuarantinedUntil:    h.quarantinedUntil,
+		}
+	}
+	return stats
+}
+
+// ResolverStats returns observability data for every resolver the
+// client has queried, as tracked by its ResolverSelector.
+func (c *Client) ResolverStats() map[string]ResolverStat {
+	return c.selector.Stats()
+}
+
+// WithResolverSelector overrides the client's default ResolverSelector,
+// e.g. to inject a fake for tests or a custom load-balancing policy.
+func (c *Client) WithResolverSelector(selector ResolverSelector) *Client {
+	c.selector = selector
+	return c
+}
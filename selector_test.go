@@ -0,0 +1,61 @@
+package dnsify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHealthAwareSelectorPrefersHealthyResolver tests that a resolver
+// with a perfect success rate is favored over one that keeps failing.
+func TestHealthAwareSelectorPrefersHealthyResolver(t *testing.T) {
+	selector := NewHealthAwareSelector()
+	resolvers := []string{"good", "bad"}
+
+	for i := 0; i < 20; i++ {
+		selector.Report("good", 5*time.Millisecond, true)
+		selector.Report("bad", 5*time.Millisecond, false)
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		counts[selector.Select(resolvers)]++
+	}
+
+	assert.Greater(t, counts["good"], counts["bad"])
+}
+
+// TestHealthAwareSelectorQuarantinesAfterFailureThreshold tests that a
+// resolver is quarantined once its consecutive-failure count reaches
+// failureThreshold, and that Select falls back to it when nothing else
+// is eligible.
+func TestHealthAwareSelectorQuarantinesAfterFailureThreshold(t *testing.T) {
+	selector := NewHealthAwareSelector()
+
+	for i := 0; i < failureThreshold; i++ {
+		selector.Report("flaky", time.Millisecond, false)
+	}
+
+	stats := selector.Stats()["flaky"]
+	assert.Equal(t, failureThreshold, stats.ConsecutiveFailures)
+	assert.True(t, stats.Quarantined)
+
+	// With only the quarantined resolver available, Select must still
+	// return it rather than panicking or blocking.
+	assert.Equal(t, "flaky", selector.Select([]string{"flaky"}))
+}
+
+// TestHealthAwareSelectorReportResetsOnSuccess tests that a successful
+// report clears the consecutive-failure streak.
+func TestHealthAwareSelectorReportResetsOnSuccess(t *testing.T) {
+	selector := NewHealthAwareSelector()
+
+	selector.Report("resolver", time.Millisecond, false)
+	selector.Report("resolver", time.Millisecond, false)
+	selector.Report("resolver", time.Millisecond, true)
+
+	stats := selector.Stats()["resolver"]
+	assert.Equal(t, 0, stats.ConsecutiveFailures)
+	assert.False(t, stats.Quarantined)
+}
@@ -0,0 +1,89 @@
+package dnsify
+
+import (
+	"fmt"
+
+	"github.com/miekg/dns"
+)
+
+// tsigOptions holds the TSIG key used to authenticate zone transfers.
+type tsigOptions struct {
+	enabled   bool
+	name      string
+	algorithm string
+	secret    string
+}
+
+// WithTSIG enables TSIG authentication for AXFR/IXFR transfers using the
+// given key name, algorithm (e.g. dns.HmacSHA256), and base64-encoded
+// secret.
+func (c *Client) WithTSIG(name, algorithm, secret string) *Client {
+	c.tsig = tsigOptions{
+		enabled:   true,
+		name:      dns.Fqdn(name),
+		algorithm: algorithm,
+		secret:    secret,
+	}
+	return c
+}
+
+// newTransfer builds a dns.Transfer configured with the client's TSIG
+// key, if one has been set via WithTSIG.
+func (c *Client) newTransfer() *dns.Transfer {
+	xfr := &dns.Transfer{}
+	if c.tsig.enabled {
+		xfr.TsigSecret = map[string]string{c.tsig.name: c.tsig.secret}
+	}
+	return xfr
+}
+
+// buildTransferRequest constructs an AXFR/IXFR query for zone, attaching
+// a TSIG signature when the client has one configured.
+func (c *Client) buildTransferRequest(zone string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(zone), qtype)
+	if c.tsig.enabled {
+		msg.SetTsig(c.tsig.name, c.tsig.algorithm, 300, 0)
+	}
+	return msg
+}
+
+// AXFR performs a full zone transfer of zone from resolver, streaming
+// envelopes to the caller as they arrive. The channel is closed when the
+// transfer completes or fails; a failure mid-stream is reported as an
+// envelope with a non-nil Error.
+func (c *Client) AXFR(zone, resolver string) (<-chan *dns.Envelope, error) {
+	msg := c.buildTransferRequest(zone, dns.TypeAXFR)
+	_, address := splitResolver(resolver)
+
+	xfr := c.newTransfer()
+	env, err := xfr.In(msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("axfr %s from %s: %w", zone, resolver, err)
+	}
+
+	return env, nil
+}
+
+// IXFR performs an incremental zone transfer of zone from resolver
+// starting at serial, streaming envelopes to the caller as they arrive.
+// The server falls back to a full AXFR if it cannot serve an incremental
+// transfer from serial.
+func (c *Client) IXFR(zone string, serial uint32, resolver string) (<-chan *dns.Envelope, error) {
+	msg := c.buildTransferRequest(zone, dns.TypeIXFR)
+	msg.Ns = []dns.RR{
+		&dns.SOA{
+			Hdr:    dns.RR_Header{Name: dns.Fqdn(zone), Rrtype: dns.TypeSOA, Class: dns.ClassINET},
+			Serial: serial,
+		},
+	}
+
+	_, address := splitResolver(resolver)
+	xfr := c.newTransfer()
+	env, err := xfr.In(msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("ixfr %s from %s: %w", zone, resolver, err)
+	}
+
+	return env, nil
+}
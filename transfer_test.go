@@ -0,0 +1,69 @@
+package dnsify
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const axfrZone = "xfr.example."
+
+// axfrHandler replies to an AXFR request with a single SOA-bracketed
+// envelope: SOA, one A record, then SOA again.
+func axfrHandler(w dns.ResponseWriter, req *dns.Msg) {
+	soa, _ := dns.NewRR(axfrZone + " 3600 IN SOA ns.example. hostmaster.example. 1 3600 900 604800 120")
+	a, _ := dns.NewRR(axfrZone + " 3600 IN A 192.0.2.20")
+
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Answer = []dns.RR{soa, a, soa}
+	_ = w.WriteMsg(m)
+}
+
+// TestAXFR tests that AXFR streams every envelope's RRs back to the
+// caller for a SOA-bracketed transfer.
+func TestAXFR(t *testing.T) {
+	const addr = "127.0.0.1:53538"
+
+	dns.HandleFunc(axfrZone, axfrHandler)
+	defer dns.HandleRemove(axfrZone)
+
+	started := make(chan struct{})
+	server := &dns.Server{Addr: addr, Net: "tcp", NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ListenAndServe() }()
+	defer server.Shutdown()
+	<-started
+
+	client := New([]string{addr}, 3)
+
+	envelopes, err := client.AXFR(axfrZone, addr)
+	require.NoError(t, err)
+
+	var rrs []dns.RR
+	for env := range envelopes {
+		assert.NoError(t, env.Error)
+		rrs = append(rrs, env.RR...)
+	}
+
+	assert.Len(t, rrs, 3)
+	_, firstIsSOA := rrs[0].(*dns.SOA)
+	_, lastIsSOA := rrs[len(rrs)-1].(*dns.SOA)
+	assert.True(t, firstIsSOA)
+	assert.True(t, lastIsSOA)
+}
+
+// TestBuildTransferRequestWithTSIG tests that WithTSIG causes transfer
+// requests to carry a TSIG signature.
+func TestBuildTransferRequestWithTSIG(t *testing.T) {
+	client := New([]string{mockDNS}, 3).WithTSIG("key.example.", dns.HmacSHA256, "c2VjcmV0Zm9ydGVzdGluZw==")
+
+	msg := client.buildTransferRequest(axfrZone, dns.TypeAXFR)
+
+	assert.NotEmpty(t, msg.Extra)
+	tsig, ok := msg.Extra[len(msg.Extra)-1].(*dns.TSIG)
+	assert.True(t, ok)
+	assert.Equal(t, "key.example.", tsig.Hdr.Name)
+	assert.Equal(t, dns.HmacSHA256, tsig.Algorithm)
+}
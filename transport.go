@@ -0,0 +1,134 @@
+package dnsify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultTLSPort = "853"
+
+	protoUDP   = "udp"
+	protoTCP   = "tcp"
+	protoTLS   = "tls"
+	protoHTTPS = "https"
+)
+
+// newTransports builds the set of protocol-specific DNS clients used to
+// exchange queries with resolvers, depending on the scheme in front of
+// each resolver address (udp://, tcp://, tls://, https://).
+func newTransports() (udpClient, tcpClient, tlsClient *dns.Client, httpClient *http.Client) {
+	udpClient = &dns.Client{Net: "udp"}
+	tcpClient = &dns.Client{Net: "tcp"}
+	tlsClient = &dns.Client{Net: "tcp-tls", TLSConfig: &tls.Config{}}
+	httpClient = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	return
+}
+
+// splitResolver parses a resolver string into its protocol and dial
+// address, defaulting to UDP on port 53 when no scheme is present.
+func splitResolver(resolver string) (protocol, address string) {
+	switch {
+	case strings.HasPrefix(resolver, protoHTTPS+"://"):
+		return protoHTTPS, resolver
+	case strings.HasPrefix(resolver, protoTLS+"://"):
+		protocol, address = protoTLS, strings.TrimPrefix(resolver, protoTLS+"://")
+	case strings.HasPrefix(resolver, protoTCP+"://"):
+		protocol, address = protoTCP, strings.TrimPrefix(resolver, protoTCP+"://")
+	case strings.HasPrefix(resolver, protoUDP+"://"):
+		protocol, address = protoUDP, strings.TrimPrefix(resolver, protoUDP+"://")
+	default:
+		protocol, address = protoUDP, resolver
+	}
+
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		port := defaultPort
+		if protocol == protoTLS {
+			port = defaultTLSPort
+		}
+		address = net.JoinHostPort(address, port)
+	}
+
+	return protocol, address
+}
+
+// exchange sends msg to resolver over the protocol encoded in its scheme,
+// transparently retrying a truncated UDP response over TCP. It honors
+// ctx cancellation for every transport.
+func (c *Client) exchange(ctx context.Context, msg *dns.Msg, resolver string) (*dns.Msg, error) {
+	protocol, address := splitResolver(resolver)
+
+	switch protocol {
+	case protoHTTPS:
+		return c.exchangeDoH(ctx, msg, address)
+	case protoTLS:
+		resp, _, err := c.tlsClient.ExchangeContext(ctx, msg, address)
+		return resp, err
+	case protoTCP:
+		resp, _, err := c.tcpClient.ExchangeContext(ctx, msg, address)
+		return resp, err
+	default:
+		resp, _, err := c.udpClient.ExchangeContext(ctx, msg, address)
+		if err != nil {
+			return resp, err
+		}
+		if resp != nil && resp.Truncated {
+			resp, _, err = c.tcpClient.ExchangeContext(ctx, msg, address)
+		}
+		return resp, err
+	}
+}
+
+// exchangeDoH performs a DNS-over-HTTPS exchange using the RFC 8484
+// wire-format POST method.
+func (c *Client) exchangeDoH(ctx context.Context, msg *dns.Msg, endpoint string) (*dns.Msg, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("doh: unexpected status code %d from %s", resp.StatusCode, endpoint)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
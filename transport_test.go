@@ -0,0 +1,123 @@
+package dnsify
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitResolver tests that resolver strings are parsed into the
+// protocol and dial address implied by their scheme, defaulting to UDP
+// on the standard DNS port when no scheme is present.
+func TestSplitResolver(t *testing.T) {
+	tests := []struct {
+		resolver    string
+		wantProto   string
+		wantAddress string
+	}{
+		{"8.8.8.8", protoUDP, "8.8.8.8:53"},
+		{"8.8.8.8:5353", protoUDP, "8.8.8.8:5353"},
+		{"udp://8.8.8.8", protoUDP, "8.8.8.8:53"},
+		{"tcp://8.8.8.8", protoTCP, "8.8.8.8:53"},
+		{"tls://1.1.1.1", protoTLS, "1.1.1.1:853"},
+		{"tls://1.1.1.1:8853", protoTLS, "1.1.1.1:8853"},
+		{"https://dns.google/dns-query", protoHTTPS, "https://dns.google/dns-query"},
+	}
+
+	for _, tt := range tests {
+		protocol, address := splitResolver(tt.resolver)
+		assert.Equal(t, tt.wantProto, protocol, tt.resolver)
+		assert.Equal(t, tt.wantAddress, address, tt.resolver)
+	}
+}
+
+// TestExchangeTCPFallbackOnTruncation tests that a truncated UDP
+// response is transparently retried over TCP.
+func TestExchangeTCPFallbackOnTruncation(t *testing.T) {
+	const addr = "127.0.0.1:53537"
+
+	dns.HandleFunc("tcfallback.example.", func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		if _, ok := w.RemoteAddr().(*net.UDPAddr); ok {
+			m.Truncated = true
+			_ = w.WriteMsg(m)
+			return
+		}
+
+		rr, _ := dns.NewRR("tcfallback.example. 3600 IN A 192.0.2.9")
+		m.Answer = append(m.Answer, rr)
+		_ = w.WriteMsg(m)
+	})
+	defer dns.HandleRemove("tcfallback.example.")
+
+	udpStarted := make(chan struct{})
+	tcpStarted := make(chan struct{})
+	udpServer := &dns.Server{Addr: addr, Net: "udp", NotifyStartedFunc: func() { close(udpStarted) }}
+	tcpServer := &dns.Server{Addr: addr, Net: "tcp", NotifyStartedFunc: func() { close(tcpStarted) }}
+	go func() { _ = udpServer.ListenAndServe() }()
+	go func() { _ = tcpServer.ListenAndServe() }()
+	defer udpServer.Shutdown()
+	defer tcpServer.Shutdown()
+	<-udpStarted
+	<-tcpStarted
+
+	client := New([]string{addr}, 3)
+	msg := buildDNSMessage("tcfallback.example", dns.TypeA)
+
+	resp, err := client.exchange(context.Background(), msg, addr)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.False(t, resp.Truncated)
+	assert.Equal(t, "192.0.2.9", resp.Answer[0].(*dns.A).A.String())
+}
+
+// TestExchangeDoH tests that a DNS-over-HTTPS exchange packs the query
+// and unpacks the response using the RFC 8484 wire format.
+func TestExchangeDoH(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/dns-message", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		query := new(dns.Msg)
+		if err := query.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		reply := new(dns.Msg)
+		reply.SetReply(query)
+		rr, _ := dns.NewRR("doh.example. 3600 IN A 192.0.2.10")
+		reply.Answer = append(reply.Answer, rr)
+
+		packed, err := reply.Pack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(packed)
+	}))
+	defer server.Close()
+
+	client := New([]string{}, 3)
+	msg := buildDNSMessage("doh.example", dns.TypeA)
+
+	resp, err := client.exchangeDoH(context.Background(), msg, server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.10", resp.Answer[0].(*dns.A).A.String())
+}